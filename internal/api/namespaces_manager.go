@@ -9,15 +9,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/vmware/octant/internal/cluster"
 	"github.com/vmware/octant/internal/event"
 	"github.com/vmware/octant/internal/log"
 	"github.com/vmware/octant/internal/octant"
+	"github.com/vmware/octant/pkg/store"
 )
 
+// namespacesWatchDebounce is how long runWatch waits after a burst of
+// ADDED/DELETED events before pushing a CreateNamespacesEvent, so that a
+// batch of namespace changes collapses into a single update.
+const namespacesWatchDebounce = 500 * time.Millisecond
+
 // NamespaceManagerConfig is configuration for NamespacesManager.
 type NamespaceManagerConfig interface {
 	ClusterClient() cluster.ClientInterface
@@ -27,7 +36,12 @@ type NamespaceManagerConfig interface {
 type NamespacesManagerOption func(n *NamespacesManager)
 
 // NamespacesGenerateFunc is a function that generates a list of namespaces.
-type NamespacesGenerateFunc func(ctx context.Context, config NamespaceManagerConfig) ([]string, error)
+type NamespacesGenerateFunc func(ctx context.Context, config NamespaceManagerConfig) ([]store.Namespace, error)
+
+// NamespacesWatchFunc is a function that establishes a watch on the
+// Namespace resource. It returns an error when the watch cannot be
+// established, e.g. RBAC denied the watch verb.
+type NamespacesWatchFunc func(ctx context.Context, config NamespaceManagerConfig) (watch.Interface, error)
 
 // WithNamespacesGenerator configures the namespaces generator function.
 func WithNamespacesGenerator(fn NamespacesGenerateFunc) NamespacesManagerOption {
@@ -43,14 +57,72 @@ func WithNamespacesGeneratorPoller(poller Poller) NamespacesManagerOption {
 	}
 }
 
+// WithNamespacesWatcher configures the namespaces watch function. When set,
+// NamespacesManager prefers watching the Namespace resource over polling,
+// and only falls back to the poller when the watch cannot be established
+// or is lost.
+func WithNamespacesWatcher(fn NamespacesWatchFunc) NamespacesManagerOption {
+	return func(n *NamespacesManager) {
+		n.namespacesWatcherFunc = fn
+	}
+}
+
 // NamespacesManager manages namespaces.
 type NamespacesManager struct {
 	config                  NamespaceManagerConfig
 	namespacesGeneratorFunc NamespacesGenerateFunc
+	namespacesWatcherFunc   NamespacesWatchFunc
 	poller                  Poller
+
+	mu         sync.RWMutex
+	namespaces []store.Namespace
+	client     OctantClient
+
+	// pushMu serializes pushNamespaces, since it can now be invoked both
+	// from this manager's own watch/poll loop and, via Refresh, from
+	// whatever goroutine handles a plugin's CreateNamespace call.
+	pushMu sync.Mutex
 }
 
 var _ StateManager = (*NamespacesManager)(nil)
+var _ store.NamespaceResolver = (*NamespacesManager)(nil)
+
+// Resolve resolves a logical namespace name to the Kubernetes namespace it
+// is backed by, so that store.Key.Namespace values supplied by plugins can
+// be rewritten to the physical namespace. Namespaces without an alias
+// resolve to themselves.
+func (n *NamespacesManager) Resolve(name string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for _, namespace := range n.namespaces {
+		if namespace.Name == name {
+			return namespace.SourceName
+		}
+	}
+
+	return name
+}
+
+// Refresh immediately regenerates and pushes the namespaces list instead
+// of waiting for the next watch event or poll tick. It is exported so that
+// a future dashboard API CreateNamespace handler can call it after a
+// successful, non-dry-run create, so the new namespace shows up in the UI
+// without delay; no such handler exists in this tree yet (see the
+// CreateNamespace doc comment in pkg/plugin/api/namespace.go), so nothing
+// in this tree calls it today. It is safe to call concurrently with the
+// watch/poll loop's own pushes: pushNamespaces serializes on pushMu.
+func (n *NamespacesManager) Refresh(ctx context.Context) {
+	n.mu.RLock()
+	client := n.client
+	n.mu.RUnlock()
+
+	if client == nil {
+		return
+	}
+
+	n.pushNamespaces(ctx, client, log.From(ctx))
+}
 
 // NewNamespacesManager creates an instance of NamespacesManager.
 func NewNamespacesManager(config NamespaceManagerConfig, options ...NamespacesManagerOption) *NamespacesManager {
@@ -58,6 +130,7 @@ func NewNamespacesManager(config NamespaceManagerConfig, options ...NamespacesMa
 		config:                  config,
 		poller:                  NewInterruptiblePoller("namespaces"),
 		namespacesGeneratorFunc: NamespacesGenerator,
+		namespacesWatcherFunc:   NamespacesWatcher,
 	}
 
 	for _, option := range options {
@@ -72,17 +145,104 @@ func (n NamespacesManager) Handlers() []octant.ClientRequestHandler {
 	return nil
 }
 
-// Start starts the manager. It periodically generates a list of namespaces.
+// Start starts the manager. It watches the Namespace resource and pushes a
+// CreateNamespacesEvent whenever namespaces are added or deleted. If the
+// watch cannot be established, it falls back to polling.
 func (n *NamespacesManager) Start(ctx context.Context, state octant.State, s OctantClient) {
+	logger := log.From(ctx)
+
+	n.mu.Lock()
+	n.client = s
+	n.mu.Unlock()
+
+	w, err := n.namespacesWatcherFunc(ctx, n.config)
+	if err != nil {
+		logger.WithErr(err).Debugf("establish namespaces watch; falling back to poller")
+		n.startPoller(ctx, s)
+		return
+	}
+
+	n.runWatch(ctx, w, s, logger)
+}
+
+func (n *NamespacesManager) startPoller(ctx context.Context, client OctantClient) {
 	ch := make(chan struct{}, 1)
 	defer func() {
 		close(ch)
 	}()
 
-	n.poller.Run(ctx, ch, n.runUpdate(state, s), event.DefaultScheduleDelay)
+	n.poller.Run(ctx, ch, n.runUpdate(client), event.DefaultScheduleDelay)
 }
 
-func (n *NamespacesManager) runUpdate(state octant.State, client OctantClient) PollerFunc {
+// runWatch consumes ADDED/DELETED events from w, coalescing bursts with
+// namespacesWatchDebounce before pushing a single CreateNamespacesEvent. If
+// the watch channel closes (e.g. the connection to the cluster is lost), it
+// falls back to the poller for the remainder of the manager's lifetime.
+//
+// The debounce timer is only ever read or reset from this goroutine's
+// select loop, so a burst of events can never fire two overlapping timer
+// callbacks the way time.AfterFunc plus Reset could. pushNamespaces can
+// still be called concurrently with this loop via Refresh; it serializes
+// those calls itself on pushMu.
+func (n *NamespacesManager) runWatch(ctx context.Context, w watch.Interface, client OctantClient, logger log.Logger) {
+	defer w.Stop()
+
+	timer := time.NewTimer(namespacesWatchDebounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				logger.Debugf("namespaces watch closed; falling back to poller")
+				n.startPoller(ctx, client)
+				return
+			}
+
+			switch e.Type {
+			case watch.Added, watch.Deleted:
+				if pending && !timer.Stop() {
+					<-timer.C
+				}
+				pending = true
+				timer.Reset(namespacesWatchDebounce)
+			}
+		case <-timer.C:
+			pending = false
+			n.pushNamespaces(ctx, client, logger)
+		}
+	}
+}
+
+func (n *NamespacesManager) pushNamespaces(ctx context.Context, client OctantClient, logger log.Logger) {
+	n.pushMu.Lock()
+	defer n.pushMu.Unlock()
+
+	namespaces, err := n.namespacesGeneratorFunc(ctx, n.config)
+	if err != nil {
+		logger.WithErr(err).Errorf("load namespaces")
+		return
+	}
+
+	n.setNamespaces(namespaces)
+	client.Send(CreateNamespacesEvent(namespaces))
+}
+
+func (n *NamespacesManager) setNamespaces(namespaces []store.Namespace) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.namespaces = namespaces
+}
+
+func (n *NamespacesManager) runUpdate(client OctantClient) PollerFunc {
 	var previous []byte
 
 	return func(ctx context.Context) bool {
@@ -103,6 +263,7 @@ func (n *NamespacesManager) runUpdate(state octant.State, client OctantClient) P
 
 			if bytes.Compare(previous, cur) != 0 {
 				previous = cur
+				n.setNamespaces(namespaces)
 				client.Send(CreateNamespacesEvent(namespaces))
 			}
 		}
@@ -111,8 +272,11 @@ func (n *NamespacesManager) runUpdate(state octant.State, client OctantClient) P
 	}
 }
 
-// NamespacesGenerator generates a list of namespaces.
-func NamespacesGenerator(_ context.Context, config NamespaceManagerConfig) ([]string, error) {
+// NamespacesGenerator generates a list of namespaces. Namespaces produced
+// this way are never aliased: Name and SourceName are the same physical
+// namespace. Use a NamespaceResolver-aware NamespacesGenerateFunc to expose
+// virtual namespaces.
+func NamespacesGenerator(_ context.Context, config NamespaceManagerConfig) ([]store.Namespace, error) {
 	if config == nil {
 		return nil, errors.New("namespaces manager config is nil")
 	}
@@ -129,15 +293,38 @@ func NamespacesGenerator(_ context.Context, config NamespaceManagerConfig) ([]st
 		names = []string{initialNamespace}
 	}
 
-	return names, nil
+	return store.NamespacesFromNames(names), nil
+}
+
+// NamespacesWatcher establishes a watch on the Namespace resource using the
+// cluster's informer-backed client.
+func NamespacesWatcher(ctx context.Context, config NamespaceManagerConfig) (watch.Interface, error) {
+	if config == nil {
+		return nil, errors.New("namespaces manager config is nil")
+	}
+
+	clusterClient := config.ClusterClient()
+	namespaceClient, err := clusterClient.NamespaceClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve namespaces client")
+	}
+
+	w, err := namespaceClient.Watch(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "watch namespaces")
+	}
+
+	return w, nil
 }
 
-// CreateNamespacesEvent creates a namespaces event.
-func CreateNamespacesEvent(namespaces []string) octant.Event {
+// CreateNamespacesEvent creates a namespaces event. The frontend only
+// cares about the logical name a namespace is presented under, so the
+// event payload stays a plain list of names for compatibility.
+func CreateNamespacesEvent(namespaces []store.Namespace) octant.Event {
 	return octant.Event{
 		Type: octant.EventTypeNamespaces,
 		Data: map[string]interface{}{
-			"namespaces": namespaces,
+			"namespaces": store.NamespaceNames(namespaces),
 		},
 	}
 }
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package api
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/vmware/octant/internal/log"
+	"github.com/vmware/octant/internal/octant"
+	"github.com/vmware/octant/pkg/store"
+)
+
+type fakeOctantClient struct {
+	mu    sync.Mutex
+	sends []octant.Event
+}
+
+func (f *fakeOctantClient) Send(event octant.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sends = append(f.sends, event)
+}
+
+func (f *fakeOctantClient) sendCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.sends)
+}
+
+func TestNamespacesManager_runWatch_coalescesBursts(t *testing.T) {
+	n := &NamespacesManager{
+		namespacesGeneratorFunc: func(_ context.Context, _ NamespaceManagerConfig) ([]store.Namespace, error) {
+			return store.NamespacesFromNames([]string{"default"}), nil
+		},
+	}
+
+	fakeWatch := watch.NewFake()
+	client := &fakeOctantClient{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		n.runWatch(ctx, fakeWatch, client, log.From(context.Background()))
+		close(done)
+	}()
+
+	for i := 0; i < 5; i++ {
+		fakeWatch.Add(nil)
+	}
+
+	// The burst above should collapse into a single push once the debounce
+	// elapses, not one push per event.
+	time.Sleep(namespacesWatchDebounce + 200*time.Millisecond)
+	assert.Equal(t, 1, client.sendCount())
+
+	cancel()
+	<-done
+}
+
+func TestNamespacesManager_runWatch_fallsBackToPollerOnClose(t *testing.T) {
+	n := NewNamespacesManager(nil)
+	n.namespacesGeneratorFunc = func(_ context.Context, _ NamespaceManagerConfig) ([]store.Namespace, error) {
+		return nil, nil
+	}
+	n.poller = NewInterruptiblePoller("test-namespaces")
+
+	fakeWatch := watch.NewFake()
+	client := &fakeOctantClient{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		n.runWatch(ctx, fakeWatch, client, log.From(context.Background()))
+		close(done)
+	}()
+
+	fakeWatch.Stop()
+
+	// Once the watch channel closes, runWatch hands off to the poller, which
+	// keeps running until ctx is cancelled; its update pushes the generated
+	// namespaces.
+	deadline := time.After(5 * time.Second)
+	for client.sendCount() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("poller did not push namespaces after the watch channel closed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("runWatch did not return after ctx was cancelled")
+	}
+}
+
+func TestNamespacesManager_Resolve(t *testing.T) {
+	n := &NamespacesManager{
+		namespaces: []store.Namespace{
+			{Name: "team-a", SourceName: "shared-infra"},
+		},
+	}
+
+	require.Equal(t, "shared-infra", n.Resolve("team-a"))
+	require.Equal(t, "unaliased", n.Resolve("unaliased"))
+}
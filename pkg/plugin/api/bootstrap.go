@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// caCertLifetime covers the life of the Octant process: the CA is
+// generated once at startup, held in memory, and used to sign every
+// plugin's leaf certificate for as long as the dashboard runs, which can
+// be hours or days. It must outlive every leaf certificate it signs.
+const caCertLifetime = 24 * time.Hour * 365
+
+// pluginCertLifetime is short relative to caCertLifetime, but long enough
+// that LeafRenewBefore gives a caller a comfortable window to renew before
+// a long-running plugin's identity actually lapses.
+const pluginCertLifetime = 1 * time.Hour
+
+// LeafRenewBefore is how long before a PluginIdentity's certificate
+// expires that callers should call RenewPluginIdentity. Octant spawns a
+// plugin once and keeps it running for the life of the dashboard session,
+// not as a short request-scoped process, so the identity handed to it at
+// spawn time must be renewed well before NotAfter or reconnects (e.g. the
+// watch stream in watch.go) start failing.
+const LeafRenewBefore = 10 * time.Minute
+
+// PluginIdentity is the credential material Octant hands to a plugin
+// process it spawns, so the plugin can dial back over mTLS and so Octant
+// can tell plugins apart on its server side. A compromised plugin only
+// ever holds its own identity, never another plugin's: GRPCServer checks
+// the connecting peer's certificate CommonName and Token against the
+// identity it was created for, via WithExpectedPluginIdentity.
+type PluginIdentity struct {
+	PluginName string
+	CertPEM    []byte
+	KeyPEM     []byte
+	Token      string
+	ExpiresAt  time.Time
+}
+
+// NeedsRenewal reports whether this identity's certificate expires within
+// LeafRenewBefore of now, and should be replaced by RenewPluginIdentity.
+func (p PluginIdentity) NeedsRenewal(now time.Time) bool {
+	return !now.Before(p.ExpiresAt.Add(-LeafRenewBefore))
+}
+
+// RenewPluginIdentity issues a fresh PluginIdentity for the same plugin,
+// signed by the same CA, ahead of the current one's expiry. Callers should
+// poll NeedsRenewal and reconnect the plugin (new TLS config, new Watch
+// streams) with the renewed identity before the old one lapses.
+func RenewPluginIdentity(identity PluginIdentity, caCertPEM, caKeyPEM []byte) (PluginIdentity, error) {
+	return NewPluginIdentity(identity.PluginName, caCertPEM, caKeyPEM)
+}
+
+// TLSConfig builds a client *tls.Config trusting caPEM and presenting this
+// identity's certificate, suitable for passing to WithTLSConfig.
+func (p PluginIdentity) TLSConfig(caPEM []byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(p.CertPEM, p.KeyPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse plugin identity certificate")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("no certificates found in CA PEM")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}, nil
+}
+
+// GenerateSelfSignedCA generates an in-memory CA for bootstrapping mTLS
+// between Octant and the plugins it spawns locally. The CA and its key
+// never leave the Octant process.
+//
+// Nothing in this tree calls GenerateSelfSignedCA yet: the plugin spawn
+// code that would own the CA's lifetime and hand out PluginIdentity
+// values via NewPluginIdentity lives outside this snapshot.
+func GenerateSelfSignedCA() (caCertPEM, caKeyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "generate CA key")
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "octant-plugin-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(caCertLifetime),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "create CA certificate")
+	}
+
+	caCertPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "marshal CA key")
+	}
+	caKeyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return caCertPEM, caKeyPEM, nil
+}
+
+// NewPluginIdentity issues a short-lived leaf certificate and token for
+// pluginName, signed by the CA produced by GenerateSelfSignedCA. Octant
+// passes the resulting PluginIdentity to a spawned plugin over its
+// environment so the plugin can dial Octant's dashboard API over mTLS, and
+// Octant records the token so it can reject requests bearing a different
+// plugin's identity.
+func NewPluginIdentity(pluginName string, caCertPEM, caKeyPEM []byte) (PluginIdentity, error) {
+	caCert, err := parseCertPEM(caCertPEM)
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "parse CA certificate")
+	}
+
+	caKey, err := parseECKeyPEM(caKeyPEM)
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "parse CA key")
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "generate plugin key")
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(0).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "generate plugin serial")
+	}
+
+	notAfter := time.Now().Add(pluginCertLifetime)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: pluginName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &leafKey.PublicKey, caKey)
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "create plugin certificate")
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	keyDER, err := x509.MarshalECPrivateKey(leafKey)
+	if err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "marshal plugin key")
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return PluginIdentity{}, errors.Wrap(err, "generate plugin token")
+	}
+
+	return PluginIdentity{
+		PluginName: pluginName,
+		CertPEM:    certPEM,
+		KeyPEM:     keyPEM,
+		Token:      fmtToken(token),
+		ExpiresAt:  notAfter,
+	}, nil
+}
+
+func parseCertPEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseECKeyPEM(keyPEM []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+func fmtToken(b []byte) string {
+	const hexDigits = "0123456789abcdef"
+
+	out := make([]byte, len(b)*2)
+	for i, v := range b {
+		out[i*2] = hexDigits[v>>4]
+		out[i*2+1] = hexDigits[v&0x0f]
+	}
+	return string(out)
+}
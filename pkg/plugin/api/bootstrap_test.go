@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSelfSignedCA(t *testing.T) {
+	caCertPEM, caKeyPEM, err := GenerateSelfSignedCA()
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(caCertPEM)
+	require.NotNil(t, block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	assert.True(t, cert.IsCA)
+	assert.True(t, cert.NotAfter.Sub(time.Now()) > pluginCertLifetime,
+		"CA must outlive the leaf certificates it signs")
+	assert.NotEmpty(t, caKeyPEM)
+}
+
+func TestNewPluginIdentity(t *testing.T) {
+	caCertPEM, caKeyPEM, err := GenerateSelfSignedCA()
+	require.NoError(t, err)
+
+	identity, err := NewPluginIdentity("test-plugin", caCertPEM, caKeyPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-plugin", identity.PluginName)
+	assert.NotEmpty(t, identity.Token)
+	assert.False(t, identity.ExpiresAt.IsZero())
+
+	block, _ := pem.Decode(identity.CertPEM)
+	require.NotNil(t, block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-plugin", cert.Subject.CommonName)
+	assert.WithinDuration(t, identity.ExpiresAt, cert.NotAfter, time.Second)
+
+	caCert, err := parseCertPEM(caCertPEM)
+	require.NoError(t, err)
+	assert.NoError(t, cert.CheckSignatureFrom(caCert))
+}
+
+func TestPluginIdentity_NeedsRenewal(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{
+			name:      "well before expiry",
+			expiresAt: now.Add(time.Hour),
+			want:      false,
+		},
+		{
+			name:      "within the renewal window",
+			expiresAt: now.Add(LeafRenewBefore / 2),
+			want:      true,
+		},
+		{
+			name:      "already expired",
+			expiresAt: now.Add(-time.Minute),
+			want:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity := PluginIdentity{ExpiresAt: test.expiresAt}
+			assert.Equal(t, test.want, identity.NeedsRenewal(now))
+		})
+	}
+}
+
+func TestRenewPluginIdentity(t *testing.T) {
+	caCertPEM, caKeyPEM, err := GenerateSelfSignedCA()
+	require.NoError(t, err)
+
+	original, err := NewPluginIdentity("test-plugin", caCertPEM, caKeyPEM)
+	require.NoError(t, err)
+
+	renewed, err := RenewPluginIdentity(original, caCertPEM, caKeyPEM)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.PluginName, renewed.PluginName)
+	assert.NotEqual(t, original.Token, renewed.Token)
+	assert.NotEqual(t, original.CertPEM, renewed.CertPEM)
+	assert.False(t, renewed.NeedsRenewal(time.Now()))
+}
@@ -7,8 +7,10 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/vmware/octant/internal/log"
@@ -44,6 +46,44 @@ type ClientOption func(c *Client)
 type Client struct {
 	DashboardConnection DashboardConnection
 	// dashboardClientFactory DashboardClientFactory
+
+	tlsConfig   *tls.Config
+	tlsErr      error
+	pluginToken string
+
+	namespaceResolver store.NamespaceResolver
+}
+
+// outgoingContext attaches this client's plugin token, if configured via
+// WithPluginClientIdentity, as outgoing gRPC metadata.
+func (c *Client) outgoingContext(ctx context.Context) context.Context {
+	if c.pluginToken == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, pluginTokenHeader, c.pluginToken)
+}
+
+// WithNamespaceResolver configures the client to rewrite store.Key.Namespace
+// on every List/Get/Update call through resolver, so a plugin can address a
+// logical (possibly virtual) namespace without knowing which physical
+// namespace it is backed by.
+func WithNamespaceResolver(resolver store.NamespaceResolver) ClientOption {
+	return func(c *Client) {
+		c.namespaceResolver = resolver
+	}
+}
+
+// resolveKey rewrites key.Namespace through the configured
+// NamespaceResolver. If no resolver is configured, key is returned
+// unchanged.
+func (c *Client) resolveKey(key store.Key) store.Key {
+	if c.namespaceResolver == nil {
+		return key
+	}
+
+	key.Namespace = c.namespaceResolver.Resolve(key.Namespace)
+	return key
 }
 
 var _ Service = (*Client)(nil)
@@ -57,9 +97,17 @@ func NewClient(address string, options ...ClientOption) (*Client, error) {
 		option(client)
 	}
 
+	if client.tlsErr != nil {
+		return nil, client.tlsErr
+	}
+
 	if client.DashboardConnection == nil {
-		// NOTE: is it possible to make this secure? Is it even important?
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
+		dialOption := grpc.WithInsecure()
+		if creds := client.transportCredentials(); creds != nil {
+			dialOption = grpc.WithTransportCredentials(creds)
+		}
+
+		conn, err := grpc.Dial(address, dialOption)
 		if err != nil {
 			return nil, err
 
@@ -81,12 +129,12 @@ func (c *Client) Close() error {
 func (c *Client) List(ctx context.Context, key store.Key) (*unstructured.UnstructuredList, error) {
 	client := c.DashboardConnection.Client()
 
-	keyRequest, err := convertFromKey(key)
+	keyRequest, err := convertFromKey(c.resolveKey(key))
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.List(ctx, keyRequest)
+	resp, err := client.List(c.outgoingContext(ctx), keyRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -103,12 +151,12 @@ func (c *Client) List(ctx context.Context, key store.Key) (*unstructured.Unstruc
 func (c *Client) Get(ctx context.Context, key store.Key) (*unstructured.Unstructured, bool, error) {
 	client := c.DashboardConnection.Client()
 
-	keyRequest, err := convertFromKey(key)
+	keyRequest, err := convertFromKey(c.resolveKey(key))
 	if err != nil {
 		return nil, false, err
 	}
 
-	resp, err := client.Get(ctx, keyRequest)
+	resp, err := client.Get(c.outgoingContext(ctx), keyRequest)
 	if err != nil {
 		return nil, false, err
 	}
@@ -125,6 +173,11 @@ func (c *Client) Get(ctx context.Context, key store.Key) (*unstructured.Unstruct
 func (c *Client) Update(ctx context.Context, object *unstructured.Unstructured) error {
 	client := c.DashboardConnection.Client()
 
+	if c.namespaceResolver != nil {
+		object = object.DeepCopy()
+		object.SetNamespace(c.namespaceResolver.Resolve(object.GetNamespace()))
+	}
+
 	data, err := convertFromObject(object)
 	if err != nil {
 		return err
@@ -134,7 +187,7 @@ func (c *Client) Update(ctx context.Context, object *unstructured.Unstructured)
 		Object: data,
 	}
 
-	_, err = client.Update(ctx, req)
+	_, err = client.Update(c.outgoingContext(ctx), req)
 
 	return err
 }
@@ -148,7 +201,7 @@ func (c *Client) PortForward(ctx context.Context, req PortForwardRequest) (PortF
 		PodName:    req.PodName,
 		PortNumber: uint32(req.Port),
 	}
-	resp, err := client.PortForward(ctx, pfRequest)
+	resp, err := client.PortForward(c.outgoingContext(ctx), pfRequest)
 	if err != nil {
 		return PortForwardResponse{}, err
 	}
@@ -168,7 +221,7 @@ func (c *Client) CancelPortForward(ctx context.Context, id string) {
 		PortForwardID: id,
 	}
 
-	_, err := client.CancelPortForward(ctx, req)
+	_, err := client.CancelPortForward(c.outgoingContext(ctx), req)
 	if err != nil {
 		logger := log.From(ctx)
 		logger.Errorf("unable to cancel port forward: %v", err)
@@ -179,6 +232,6 @@ func (c *Client) CancelPortForward(ctx context.Context, id string) {
 func (c *Client) ForceFrontendUpdate(ctx context.Context) error {
 	client := c.DashboardConnection.Client()
 
-	_, err := client.ForceFrontendUpdate(ctx, &proto.Empty{})
+	_, err := client.ForceFrontendUpdate(c.outgoingContext(ctx), &proto.Empty{})
 	return err
 }
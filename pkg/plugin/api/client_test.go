@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/vmware/octant/pkg/store"
+)
+
+func TestClient_resolveKey(t *testing.T) {
+	c := &Client{namespaceResolver: store.NewNamespaceResolver([]store.Namespace{
+		{Name: "team-a", SourceName: "shared-infra"},
+	})}
+
+	got := c.resolveKey(store.Key{Namespace: "team-a"})
+	assert.Equal(t, store.Key{Namespace: "shared-infra"}, got)
+
+	noResolver := &Client{}
+	got = noResolver.resolveKey(store.Key{Namespace: "team-a"})
+	assert.Equal(t, store.Key{Namespace: "team-a"}, got, "key must pass through unchanged without a configured resolver")
+}
+
+func TestClient_outgoingContext(t *testing.T) {
+	c := &Client{pluginToken: "test-token"}
+
+	ctx := c.outgoingContext(context.Background())
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, []string{"test-token"}, md.Get(pluginTokenHeader))
+
+	noToken := &Client{}
+	ctx = noToken.outgoingContext(context.Background())
+	_, ok = metadata.FromOutgoingContext(ctx)
+	assert.False(t, ok, "no metadata should be attached without a configured plugin token")
+}
+
+func TestNewClient_TLSOptionComposition(t *testing.T) {
+	client := &Client{}
+	WithCAFile("testdata/does-not-exist.pem")(client)
+
+	assert.Error(t, client.tlsErr, "a missing CA file must surface as a client construction error")
+
+	_, err := NewClient("127.0.0.1:0", WithCAFile("testdata/does-not-exist.pem"))
+	assert.Error(t, err)
+}
+
+func TestNewClient_InsecureByDefault(t *testing.T) {
+	client, err := NewClient("127.0.0.1:0")
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Nil(t, client.transportCredentials(), "a client with no TLS options must dial insecurely")
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// pluginTokenHeader is the gRPC metadata key a plugin must set to its
+// PluginIdentity.Token on every call.
+const pluginTokenHeader = "x-plugin-token"
+
+// GRPCServerOption is an option for configuring GRPCServer.
+type GRPCServerOption func(s *GRPCServer)
+
+// GRPCServer wraps the grpc.Server that serves the dashboard API to a
+// single out-of-process plugin.
+type GRPCServer struct {
+	server           *grpc.Server
+	tlsConfig        *tls.Config
+	expectedIdentity *PluginIdentity
+}
+
+// WithServerTLSConfig configures the dashboard API server to require TLS,
+// using config to authenticate itself and, when config.ClientAuth is set
+// to require a client certificate, to authenticate the connecting plugin.
+//
+// Like WithExpectedPluginIdentity below, nothing in this tree constructs a
+// GRPCServer with this option yet; that's the job of whatever code starts
+// the dashboard API server per spawned plugin, which lives outside this
+// snapshot.
+func WithServerTLSConfig(config *tls.Config) GRPCServerOption {
+	return func(s *GRPCServer) {
+		s.tlsConfig = config
+	}
+}
+
+// WithExpectedPluginIdentity pins this server to the single plugin
+// identity. See PluginIdentity for what a connecting client must present
+// to satisfy it.
+func WithExpectedPluginIdentity(identity PluginIdentity) GRPCServerOption {
+	return func(s *GRPCServer) {
+		s.expectedIdentity = &identity
+	}
+}
+
+// NewGRPCServer creates a GRPCServer. When no TLS option is supplied, the
+// server falls back to an insecure listener, matching historical behavior.
+func NewGRPCServer(options ...GRPCServerOption) *GRPCServer {
+	s := &GRPCServer{}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	var serverOptions []grpc.ServerOption
+	if s.tlsConfig != nil {
+		if s.expectedIdentity != nil {
+			s.tlsConfig.VerifyPeerCertificate = s.verifyPeerCertificate
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+
+	if s.expectedIdentity != nil {
+		serverOptions = append(serverOptions,
+			grpc.UnaryInterceptor(s.unaryTokenInterceptor),
+			grpc.StreamInterceptor(s.streamTokenInterceptor),
+		)
+	}
+
+	s.server = grpc.NewServer(serverOptions...)
+
+	return s
+}
+
+// Server returns the underlying grpc.Server so callers can register the
+// DashboardServer implementation and start serving.
+func (s *GRPCServer) Server() *grpc.Server {
+	return s.server
+}
+
+// verifyPeerCertificate rejects a TLS handshake unless the first verified
+// chain's leaf certificate was issued to s.expectedIdentity.PluginName.
+// Every plugin's leaf certificate is signed by the same shared CA, so
+// RequireAndVerifyClientCert alone only proves "some plugin connected", not
+// "the plugin this server is listening for" - this closes that gap.
+func (s *GRPCServer) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if len(chain) == 0 {
+			continue
+		}
+
+		if chain[0].Subject.CommonName == s.expectedIdentity.PluginName {
+			return nil
+		}
+	}
+
+	return errors.Errorf("certificate does not match expected plugin %q", s.expectedIdentity.PluginName)
+}
+
+func (s *GRPCServer) checkToken(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing plugin token")
+	}
+
+	values := md.Get(pluginTokenHeader)
+	if len(values) != 1 || values[0] != s.expectedIdentity.Token {
+		return status.Error(codes.Unauthenticated, "invalid plugin token")
+	}
+
+	return nil
+}
+
+func (s *GRPCServer) unaryTokenInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkToken(ctx); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) streamTokenInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkToken(stream.Context()); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
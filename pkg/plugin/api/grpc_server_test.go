@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestGRPCServer_verifyPeerCertificate(t *testing.T) {
+	caCertPEM, caKeyPEM, err := GenerateSelfSignedCA()
+	require.NoError(t, err)
+
+	identity, err := NewPluginIdentity("expected-plugin", caCertPEM, caKeyPEM)
+	require.NoError(t, err)
+
+	otherIdentity, err := NewPluginIdentity("other-plugin", caCertPEM, caKeyPEM)
+	require.NoError(t, err)
+
+	s := &GRPCServer{expectedIdentity: &identity}
+
+	expectedCert, err := parseCertPEM(identity.CertPEM)
+	require.NoError(t, err)
+	otherCert, err := parseCertPEM(otherIdentity.CertPEM)
+	require.NoError(t, err)
+
+	assert.NoError(t, s.verifyPeerCertificate(nil, [][]*x509.Certificate{{expectedCert}}))
+	assert.Error(t, s.verifyPeerCertificate(nil, [][]*x509.Certificate{{otherCert}}),
+		"a certificate issued to a different plugin must not verify")
+}
+
+func TestGRPCServer_checkToken(t *testing.T) {
+	s := &GRPCServer{expectedIdentity: &PluginIdentity{Token: "expected-token"}}
+
+	okCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pluginTokenHeader, "expected-token"))
+	assert.NoError(t, s.checkToken(okCtx))
+
+	badCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pluginTokenHeader, "wrong-token"))
+	assert.Error(t, s.checkToken(badCtx))
+
+	missingCtx := context.Background()
+	assert.Error(t, s.checkToken(missingCtx))
+}
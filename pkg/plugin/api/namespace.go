@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware/octant/pkg/plugin/api/proto"
+)
+
+// CreateNamespaceOptions configures a CreateNamespace call.
+type CreateNamespaceOptions struct {
+	// Labels are applied to the created namespace.
+	Labels map[string]string
+	// Annotations are applied to the created namespace.
+	Annotations map[string]string
+	// ManifestPath, if set, points to a manifest bundle (e.g. RBAC,
+	// resource quotas, network policies) applied once the namespace is
+	// created. The path is read by the plugin and its contents sent to
+	// Octant, so the plugin does not need its own cluster credentials.
+	ManifestPath string
+	// DryRun, if true, validates the request without creating anything.
+	DryRun bool
+}
+
+// manifestBytes reads the manifest bundle at opts.ManifestPath, if set.
+func (opts CreateNamespaceOptions) manifestBytes() ([]byte, error) {
+	if opts.ManifestPath == "" {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(opts.ManifestPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "read namespace manifest")
+	}
+
+	return data, nil
+}
+
+// CreateNamespace creates a namespace through the dashboard's object
+// store, so plugins (e.g. a tenancy or provisioning plugin) don't need to
+// smuggle a Namespace manifest through Update. Once a dashboard API
+// CreateNamespace handler exists to call it, the handler should call
+// NamespacesManager.Refresh on success so the new namespace shows up
+// immediately rather than waiting for the next watch event or poll tick.
+//
+// Like Watch, Create and Delete in watch.go, this calls through to a
+// proto.DashboardClient.CreateNamespace RPC that has no .proto definition
+// or server-side handler in this tree yet.
+func (c *Client) CreateNamespace(ctx context.Context, name string, opts CreateNamespaceOptions) error {
+	client := c.DashboardConnection.Client()
+
+	manifest, err := opts.manifestBytes()
+	if err != nil {
+		return err
+	}
+
+	req := &proto.CreateNamespaceRequest{
+		Name:        name,
+		Labels:      opts.Labels,
+		Annotations: opts.Annotations,
+		Manifest:    manifest,
+		DryRun:      opts.DryRun,
+	}
+
+	_, err = client.CreateNamespace(c.outgoingContext(ctx), req)
+
+	return err
+}
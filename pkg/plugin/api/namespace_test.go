@@ -0,0 +1,48 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateNamespaceOptions_manifestBytes(t *testing.T) {
+	t.Run("no manifest path", func(t *testing.T) {
+		opts := CreateNamespaceOptions{}
+
+		data, err := opts.manifestBytes()
+		require.NoError(t, err)
+		assert.Nil(t, data)
+	})
+
+	t.Run("reads the manifest file", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "octant-namespace-manifest-*.yaml")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+
+		_, err = f.WriteString("kind: ResourceQuota\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		opts := CreateNamespaceOptions{ManifestPath: f.Name()}
+
+		data, err := opts.manifestBytes()
+		require.NoError(t, err)
+		assert.Equal(t, "kind: ResourceQuota\n", string(data))
+	})
+
+	t.Run("missing manifest file", func(t *testing.T) {
+		opts := CreateNamespaceOptions{ManifestPath: "/does/not/exist.yaml"}
+
+		_, err := opts.manifestBytes()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+// WithTLSConfig configures the client to dial the plugin API over TLS using
+// the supplied configuration. Use WithCAFile and WithClientCert for the
+// common case of building a *tls.Config from files on disk.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithCAFile configures the client to trust the CA certificate in caFile
+// when dialing the plugin API, instead of the system root CAs.
+func WithCAFile(caFile string) ClientOption {
+	return func(c *Client) {
+		pool, err := certPoolFromFile(caFile)
+		if err != nil {
+			c.tlsErr = errors.Wrap(err, "load plugin CA file")
+			return
+		}
+
+		if c.tlsConfig == nil {
+			c.tlsConfig = &tls.Config{}
+		}
+		c.tlsConfig.RootCAs = pool
+	}
+}
+
+// WithClientCert configures the client to present a client certificate for
+// mutual TLS authentication with the plugin.
+func WithClientCert(certFile, keyFile string) ClientOption {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.tlsErr = errors.Wrap(err, "load plugin client certificate")
+			return
+		}
+
+		if c.tlsConfig == nil {
+			c.tlsConfig = &tls.Config{}
+		}
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, cert)
+	}
+}
+
+// WithPluginClientIdentity configures the client with the PluginIdentity
+// Octant handed this plugin at spawn time: identity.CertPEM/KeyPEM become
+// the client's mTLS certificate (trusting caPEM), and identity.Token is
+// sent as the "x-plugin-token" header on every call. See PluginIdentity for
+// why this is what lets GRPCServer confirm a connection really is the
+// plugin it expects.
+func WithPluginClientIdentity(identity PluginIdentity, caPEM []byte) ClientOption {
+	return func(c *Client) {
+		config, err := identity.TLSConfig(caPEM)
+		if err != nil {
+			c.tlsErr = errors.Wrap(err, "build TLS config from plugin identity")
+			return
+		}
+
+		c.tlsConfig = config
+		c.pluginToken = identity.Token
+	}
+}
+
+func certPoolFromFile(caFile string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.Errorf("no certificates found in %s", caFile)
+	}
+
+	return pool, nil
+}
+
+// transportCredentials returns the transport credentials implied by the
+// client's TLS configuration, or nil if the connection should remain
+// insecure.
+func (c *Client) transportCredentials() credentials.TransportCredentials {
+	if c.tlsConfig == nil {
+		return nil
+	}
+
+	return credentials.NewTLS(c.tlsConfig)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/vmware/octant/internal/log"
+	"github.com/vmware/octant/pkg/plugin/api/proto"
+	"github.com/vmware/octant/pkg/store"
+)
+
+// watchEventBufferSize bounds the number of WatchEvents a plugin can be
+// behind the dashboard's object store before events are dropped. A slow
+// plugin loses the oldest events rather than applying backpressure to the
+// store's informers.
+const watchEventBufferSize = 64
+
+// watchReconnectBackoff bounds the client's retry delay when the Watch
+// stream is lost, e.g. because Octant restarted.
+var watchReconnectBackoff = []time.Duration{
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	2 * time.Second,
+	5 * time.Second,
+}
+
+// WatchEventType describes the kind of change a WatchEvent carries.
+type WatchEventType string
+
+const (
+	// WatchEventAdded indicates an object was added.
+	WatchEventAdded WatchEventType = "ADDED"
+	// WatchEventModified indicates an object was updated.
+	WatchEventModified WatchEventType = "MODIFIED"
+	// WatchEventDeleted indicates an object was deleted.
+	WatchEventDeleted WatchEventType = "DELETED"
+)
+
+// WatchEvent is a single change to an object matching a Watch's key.
+type WatchEvent struct {
+	Type   WatchEventType
+	Object *unstructured.Unstructured
+}
+
+// Watch streams changes to objects matching key from the dashboard's
+// object store. The returned channel is closed when ctx is cancelled, or
+// when the server ends the stream cleanly (io.EOF). If the underlying
+// stream fails with any other error, Watch reconnects with backoff rather
+// than closing the channel, so long-running plugins don't need their own
+// retry loop.
+//
+// Like the rest of this client, Watch calls through to
+// proto.DashboardClient, whose generated code and wire definitions live
+// outside this tree; landing this method for real additionally needs a
+// Watch RPC added to the dashboard's .proto service definition and a
+// server-side handler backed by the object store's informers, neither of
+// which exist in this snapshot. Create and Delete below are in the same
+// position.
+func (c *Client) Watch(ctx context.Context, key store.Key) (<-chan WatchEvent, error) {
+	keyRequest, err := convertFromKey(c.resolveKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := c.DashboardConnection.Client().Watch(c.outgoingContext(ctx), keyRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan WatchEvent, watchEventBufferSize)
+
+	go c.runWatch(ctx, keyRequest, stream, events)
+
+	return events, nil
+}
+
+func (c *Client) runWatch(ctx context.Context, keyRequest *proto.KeyRequest, stream proto.Dashboard_WatchClient, events chan WatchEvent) {
+	defer close(events)
+
+	logger := log.From(ctx)
+	attempt := 0
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.WithErr(err).Debugf("plugin watch stream lost; reconnecting")
+
+			stream, err = c.reconnectWatch(ctx, keyRequest, &attempt)
+			if err != nil {
+				logger.WithErr(err).Errorf("reconnect plugin watch stream")
+				return
+			}
+
+			continue
+		}
+
+		attempt = 0
+
+		event, err := convertToWatchEvent(resp)
+		if err != nil {
+			logger.WithErr(err).Errorf("convert watch event")
+			continue
+		}
+
+		sendDroppingOldest(events, event)
+	}
+}
+
+// sendDroppingOldest sends event on events, dropping the oldest buffered
+// event to make room if events is full, rather than blocking the store's
+// informer on a slow plugin.
+func sendDroppingOldest(events chan WatchEvent, event WatchEvent) {
+	select {
+	case events <- event:
+	default:
+		select {
+		case <-events:
+		default:
+		}
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// reconnectBackoff returns the delay to wait before the given reconnect
+// attempt (0-indexed), holding at the last configured delay once attempt
+// runs past the end of watchReconnectBackoff.
+func reconnectBackoff(attempt int) time.Duration {
+	if attempt < len(watchReconnectBackoff) {
+		return watchReconnectBackoff[attempt]
+	}
+
+	return watchReconnectBackoff[len(watchReconnectBackoff)-1]
+}
+
+func (c *Client) reconnectWatch(ctx context.Context, keyRequest *proto.KeyRequest, attempt *int) (proto.Dashboard_WatchClient, error) {
+	for {
+		delay := reconnectBackoff(*attempt)
+		*attempt++
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		stream, err := c.DashboardConnection.Client().Watch(c.outgoingContext(ctx), keyRequest)
+		if err == nil {
+			return stream, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func convertToWatchEvent(resp *proto.WatchResponse) (WatchEvent, error) {
+	object, _, err := convertToObject(resp.Object)
+	if err != nil {
+		return WatchEvent{}, err
+	}
+
+	return WatchEvent{
+		Type:   WatchEventType(resp.Type),
+		Object: object,
+	}, nil
+}
+
+// Create creates an object in the dashboard's object store.
+func (c *Client) Create(ctx context.Context, object *unstructured.Unstructured) error {
+	client := c.DashboardConnection.Client()
+
+	if c.namespaceResolver != nil {
+		object = object.DeepCopy()
+		object.SetNamespace(c.namespaceResolver.Resolve(object.GetNamespace()))
+	}
+
+	data, err := convertFromObject(object)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Create(c.outgoingContext(ctx), &proto.CreateRequest{Object: data})
+
+	return err
+}
+
+// Delete deletes the object matching key from the dashboard's object store.
+func (c *Client) Delete(ctx context.Context, key store.Key) error {
+	client := c.DashboardConnection.Client()
+
+	keyRequest, err := convertFromKey(c.resolveKey(key))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Delete(c.outgoingContext(ctx), &proto.DeleteRequest{Key: keyRequest})
+
+	return err
+}
@@ -0,0 +1,40 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendDroppingOldest(t *testing.T) {
+	events := make(chan WatchEvent, 2)
+
+	first := WatchEvent{Type: WatchEventAdded}
+	second := WatchEvent{Type: WatchEventModified}
+	third := WatchEvent{Type: WatchEventDeleted}
+
+	sendDroppingOldest(events, first)
+	sendDroppingOldest(events, second)
+
+	// events is now full; third should evict first, the oldest event.
+	sendDroppingOldest(events, third)
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, second, <-events)
+	assert.Equal(t, third, <-events)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	for attempt, want := range watchReconnectBackoff {
+		assert.Equal(t, want, reconnectBackoff(attempt))
+	}
+
+	lastDelay := watchReconnectBackoff[len(watchReconnectBackoff)-1]
+	assert.Equal(t, lastDelay, reconnectBackoff(len(watchReconnectBackoff)))
+	assert.Equal(t, lastDelay, reconnectBackoff(len(watchReconnectBackoff)+10))
+}
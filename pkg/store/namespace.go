@@ -0,0 +1,79 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+// Namespace is a namespace as presented to the dashboard. Name is the
+// logical name shown in the UI and used in store.Key.Namespace; SourceName
+// is the underlying Kubernetes namespace the logical namespace is backed
+// by. For a namespace that is not aliased, Name and SourceName are equal.
+type Namespace struct {
+	Name       string
+	SourceName string
+}
+
+// NamespacesFromNames is a compatibility shim for generators and callers
+// that only know physical namespace names. It returns a Namespace per name
+// with Name and SourceName set to the same value.
+func NamespacesFromNames(names []string) []Namespace {
+	namespaces := make([]Namespace, 0, len(names))
+	for _, name := range names {
+		namespaces = append(namespaces, Namespace{Name: name, SourceName: name})
+	}
+
+	return namespaces
+}
+
+// NamespaceNames is a compatibility shim for callers that only want the
+// logical names, e.g. for JSON payloads sent to the frontend.
+func NamespaceNames(namespaces []Namespace) []string {
+	names := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+
+	return names
+}
+
+// NamespaceResolver resolves a logical namespace, as seen in the UI and in
+// store.Key.Namespace, to the Kubernetes namespace it is actually backed
+// by. Implementations must be safe for concurrent use.
+type NamespaceResolver interface {
+	// Resolve returns the source namespace backing name. If name has no
+	// alias, it returns name unchanged.
+	Resolve(name string) string
+}
+
+// staticNamespaceResolver resolves namespaces from a fixed logical->source
+// mapping.
+type staticNamespaceResolver struct {
+	sources map[string]string
+}
+
+var _ NamespaceResolver = (*staticNamespaceResolver)(nil)
+
+// NewNamespaceResolver creates a NamespaceResolver from a list of
+// Namespaces, e.g. the list a NamespacesManager currently knows about. It
+// is meant for a plugin-side client constructed with
+// pkg/plugin/api.WithNamespaceResolver, fed a snapshot of NamespacesManager's
+// aliases; no such wiring exists in this tree yet, since there is no RPC
+// here for a plugin to fetch the namespace list it would resolve against.
+func NewNamespaceResolver(namespaces []Namespace) NamespaceResolver {
+	sources := make(map[string]string, len(namespaces))
+	for _, namespace := range namespaces {
+		sources[namespace.Name] = namespace.SourceName
+	}
+
+	return &staticNamespaceResolver{sources: sources}
+}
+
+// Resolve resolves name to its source namespace.
+func (r *staticNamespaceResolver) Resolve(name string) string {
+	if source, ok := r.sources[name]; ok {
+		return source
+	}
+
+	return name
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2019 the Octant contributors. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNamespaceResolver_Resolve(t *testing.T) {
+	resolver := NewNamespaceResolver([]Namespace{
+		{Name: "team-a", SourceName: "shared-infra"},
+		{Name: "team-b", SourceName: "team-b"},
+	})
+
+	tests := []struct {
+		name   string
+		lookup string
+		want   string
+	}{
+		{
+			name:   "aliased namespace resolves to its source",
+			lookup: "team-a",
+			want:   "shared-infra",
+		},
+		{
+			name:   "unaliased namespace resolves to itself",
+			lookup: "team-b",
+			want:   "team-b",
+		},
+		{
+			name:   "unknown namespace resolves to itself",
+			lookup: "does-not-exist",
+			want:   "does-not-exist",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, resolver.Resolve(test.lookup))
+		})
+	}
+}
+
+func TestNamespacesFromNames(t *testing.T) {
+	got := NamespacesFromNames([]string{"a", "b"})
+
+	assert.Equal(t, []Namespace{
+		{Name: "a", SourceName: "a"},
+		{Name: "b", SourceName: "b"},
+	}, got)
+}
+
+func TestNamespaceNames(t *testing.T) {
+	got := NamespaceNames([]Namespace{
+		{Name: "team-a", SourceName: "shared-infra"},
+		{Name: "team-b", SourceName: "team-b"},
+	})
+
+	assert.Equal(t, []string{"team-a", "team-b"}, got)
+}